@@ -1,299 +1,194 @@
 package api
 
 import (
-	// Assuming 'db *sql.DB' is defined globally or accessible.
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"os" // Needed for os.Exit and environment variables
+	"os"
+	"strings"
 	"testing"
+	"time"
 
-	// Consider using the official gin-contrib/cors: "github.com/gin-contrib/cors"
-	"github.com/gin-gonic/contrib/cors" // Current: "github.com/gin-gonic/contrib/cors"
+	"github.com/el10savio/TODO-Fullstack-App-Go-Gin-Postgres-React/backend/auth"
+	"github.com/el10savio/TODO-Fullstack-App-Go-Gin-Postgres-React/backend/auth/authtest"
+	"github.com/gin-gonic/contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 // router is the Gin engine used for tests.
 var router *gin.Engine
 
-// db is assumed to be your global database connection, initialized by SetupPostgres.
-// Ensure this is properly declared and initialized in your actual package structure
-// if it's not in this file. For example, in a models.go or db.go:
-// var DB *sql.DB
-// var db *sql.DB // Placeholder: replace with your actual global DB variable if named differently or in another file.
-
-// ListItem struct definition.
-// This should ideally be in a models.go or a shared types file.
-// type ListItem struct {
-// 	Id   string `json:"id"`
-// 	Item string `json:"item"`
-// 	Done bool   `json:"done"`
-// }
-
-/*
-IMPORTANT: The following functions `SetupPostgres`, `TodoItems`, `CreateTodoItem`,
-`UpdateTodoItem`, and `DeleteTodoItem` are assumed to be part of your 'api' package
-or correctly imported. Their actual implementations are in your main application files.
-The comments below highlight how they should behave for these tests to pass.
-*/
-
-// SetupPostgres (Assumed to be in your main code, e.g., db.go or main.go)
-// CRITICAL: This function MUST use environment variables for DB connection
-// parameters (DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME) so that it
-// can connect to the PostgreSQL service container in GitHub Actions.
-// In GitHub Actions CI, DB_HOST should typically be "postgres" (the service name).
-/*
-func SetupPostgres() {
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	// ... get other env vars ...
-
-	if dbHost == "" { dbHost = "localhost" } // Default for local if not set
-	if dbPort == "" { dbPort = "5432" }    // Default for local if not set
-
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		dbHost, dbPort, os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_NAME"))
+// testAPI is the API instance backing router, kept around so tests can
+// reset its Repository between runs.
+var testAPI *API
 
-	var err error
-	// 'db' should be your package-level or global database connection variable
-	db, err = sql.Open("postgres", dsn)
-	if err != nil {
-		log.Fatalf("Failed to open DB connection: %v", err)
-	}
-	if err = db.Ping(); err != nil {
-		log.Fatalf("Failed to ping DB: %v", err)
-	}
-	fmt.Println("Successfully connected to test database!")
-	// Initialize schema if needed for tests, or ensure migrations run.
-}
-*/
-
-// API Handlers (Assumed to be in your main code, e.g., handlers.go)
-/*
-func TodoItems(c *gin.Context) {
-	// Must fetch items from 'db'.
-	// If no items, MUST return: c.JSON(http.StatusOK, gin.H{"items": []ListItem{}}) // Empty slice, not nil
-	// Otherwise: c.JSON(http.StatusOK, gin.H{"items": fetchedItems})
-}
-
-func CreateTodoItem(c *gin.Context) {
-	itemName := c.Param("item")
-	// Must save to 'db'. The database should assign an ID.
-	// Must return the created item, including its new ID.
-	// e.g., c.JSON(http.StatusCreated, gin.H{"item": createdItemWithID}) // Note: test expects key "items" for single create
-}
-
-func UpdateTodoItem(c *gin.Context) {
-	// id := c.Param("id")
-	// doneStatus := c.Param("done")
-	// Must update item in 'db'.
-	// If item not found, MUST return: c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
-	// On success: c.JSON(http.StatusOK, gin.H{"item": updatedItem}) // Or just status OK
-}
-
-func DeleteTodoItem(c *gin.Context) {
-	// id := c.Param("id")
-	// Must delete item from 'db'.
-	// If item not found, MUST return: c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
-	// On success: c.JSON(http.StatusOK, gin.H{"message": "deleted"}) // Or just status OK
-}
-*/
-
-// displayTable is a test helper.
-func displayTable() {
-	if db == nil {
-		fmt.Println("displayTable: DB connection is nil. SetupPostgres might not have initialized 'db'.")
-		return
-	}
-	rows, err := db.Query("SELECT id, item, done FROM list") // Be explicit with column names
-	if err != nil {
-		fmt.Println("displayTable query error:", err.Error())
-		return
-	}
-	defer rows.Close()
-
-	items := make([]ListItem, 0)
-	for rows.Next() {
-		item := ListItem{}
-		if err := rows.Scan(&item.Id, &item.Item, &item.Done); err != nil {
-			fmt.Println("displayTable scan error:", err.Error())
-			// Optionally continue to allow partial display
-		}
-		items = append(items, item)
-	}
-	if err := rows.Err(); err != nil { // Check for errors during iteration
-		fmt.Println("displayTable rows iteration error:", err.Error())
-	}
-	fmt.Println("Current items in DB for test:", items)
-}
+// testUserID is the owner all test items are created under; testToken is a
+// bearer token minted for it.
+var (
+	testUserID = uuid.NewString()
+	testToken  string
+)
 
-// emptyTable is a test helper to clear the database state.
+// emptyTable is a test helper that resets testAPI's storage between tests,
+// giving each test a clean, empty MemoryRepository.
 func emptyTable() {
-	if db == nil {
-		fmt.Println("emptyTable: DB connection is nil. Cannot clear table. Tests will be unreliable.")
-		// This is a critical failure point if db is not initialized by SetupPostgres.
-		return
-	}
-	// Using MustExec for simplicity in tests; errors would cause panic.
-	// Or check errors explicitly:
-	if _, err := db.Exec("DELETE FROM list;"); err != nil {
-		fmt.Printf("emptyTable: Failed to delete from list: %v\n", err)
-	}
-	// Reset id counter. Ensure 'list_id_seq' is the correct sequence name for your table.
-	if _, err := db.Exec("ALTER SEQUENCE list_id_seq RESTART WITH 1;"); err != nil {
-		fmt.Printf("emptyTable: Failed to reset sequence list_id_seq: %v\n", err)
-	}
+	testAPI.repo = NewMemoryRepository()
 }
 
-// SetupRoutesForTest configures the Gin engine for testing.
-// It's good practice to have this separate if your main SetupRoutes does more (e.g., global middleware).
+// SetupRoutesForTest configures the Gin engine for testing, mirroring the
+// /items and deprecated /item routes of SetupRoutes in main.go, without its
+// tracing and Prometheus middleware or its other routes. It runs the
+// handler suite hermetically against an in-memory Repository.
 func SetupRoutesForTest() *gin.Engine {
-	r := gin.New()      // Use gin.New() for a clean engine in tests, add middleware selectively.
-	r.Use(gin.Logger()) // Optional: logger for test runs
+	testAPI = NewAPI(NewMemoryRepository())
+
+	r := gin.New()
+	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 
 	config := cors.DefaultConfig()
-	config.AllowAllOrigins = true // For testing convenience. Be stricter in production.
+	config.AllowAllOrigins = true
 	r.Use(cors.New(config))
 
-	// Routes should match your application's routes.
-	// These handlers (TodoItems, etc.) are your actual application handlers.
-	r.GET("/items", TodoItems)
-	r.GET("/item/create/:item", CreateTodoItem)
-	r.GET("/item/update/:id/:done", UpdateTodoItem)
-	r.GET("/item/delete/:id", DeleteTodoItem)
+	items := r.Group("/items", auth.RequireUser())
+	items.GET("", testAPI.TodoItems)
+	items.POST("", testAPI.CreateItem)
+	items.PATCH("/:id", testAPI.UpdateItem)
+	items.DELETE("/:id", testAPI.DeleteItem)
+	items.GET("/events", testAPI.ItemEvents)
+
+	legacy := r.Group("/item", auth.RequireUser())
+	legacy.GET("/create/:item", testAPI.CreateTodoItem)
+	legacy.GET("/update/:id/:done", testAPI.UpdateTodoItem)
+	legacy.GET("/delete/:id", testAPI.DeleteTodoItem)
 
 	return r
 }
 
-// performRequest is a test helper to make HTTP requests to the test server.
+// performRequest is a test helper to make authenticated HTTP requests to
+// the test server as testUserID.
 func performRequest(r http.Handler, method, path string) *httptest.ResponseRecorder {
 	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 	return w
 }
 
-// TestMain sets up the test environment.
-// It's run once before all tests in the package.
-func TestMain(m *testing.M) {
-	// 1. Setup Database Connection
-	// This *must* use environment variables for CI (DB_HOST=postgres, etc.)
-	// SetupPostgres() // This function (from your main code) should initialize the global 'db'.
-	// Forcing a call to a placeholder if not defined, to highlight its necessity.
-	// In your actual setup, ensure your real SetupPostgres is called.
-	if os.Getenv("CI") != "" { // Simple check if running in a CI-like environment
-		fmt.Println("TestMain: Attempting to connect to DB using ENV VARS for CI...")
+// performJSONRequest is like performRequest but encodes body as a JSON
+// request, setting the Content-Type header accordingly.
+func performJSONRequest(r http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
 	}
-	// Call your actual DB setup function here. For example:
-	// myapp.SetupPostgres() // if SetupPostgres is in myapp package
-	// This must initialize the 'db' variable used by emptyTable/displayTable.
-
-	// 2. Setup Router
-	router = SetupRoutesForTest() // Use the test-specific router setup.
-
-	// 3. Run Tests
-	exitCode := m.Run()
+	req, _ := http.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
 
-	// 4. Teardown (optional)
-	// Example: if db != nil { db.Close() }
+// TestMain sets up the test environment. It does not touch Postgres: auth
+// token issuance is stateless, and SetupRoutesForTest wires an in-memory
+// Repository, so the whole suite runs hermetically.
+func TestMain(m *testing.M) {
+	var err error
+	testToken, err = auth.IssueToken(testUserID)
+	if err != nil {
+		fmt.Println("TestMain: failed to issue test token:", err)
+		os.Exit(1)
+	}
 
-	os.Exit(exitCode)
+	router = SetupRoutesForTest()
+	os.Exit(m.Run())
 }
 
 // TestItemsGet_EmptyList tests GET /items when the database is empty.
 func TestItemsGet_EmptyList(t *testing.T) {
-	if db == nil { // Pre-condition check
-		t.Fatal("Database connection (db) is nil. Check TestMain and SetupPostgres.")
-	}
-	emptyTable() // Clear the table before the test.
+	emptyTable()
 
 	w := performRequest(router, "GET", "/items")
 	assert.Equal(t, http.StatusOK, w.Code, "Expected HTTP 200 OK")
 
-	// API Handler Requirement: Must return `{"items": []}` (empty JSON array)
-	var responseBody map[string][]ListItem
-	err := json.Unmarshal(w.Body.Bytes(), &responseBody)
-
+	var page ItemsPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
 	assert.Nil(t, err, "JSON unmarshalling should succeed")
-	if err != nil {
-		t.Logf("Response body was: %s", w.Body.String())
-	}
 
-	items, exists := responseBody["items"]
-	assert.True(t, exists, "Response should contain 'items' key")
-	assert.NotNil(t, items, "'items' array should be empty, not nil (e.g. `[]` not `null`)")
-	assert.Len(t, items, 0, "There should be 0 items in the list")
+	assert.NotNil(t, page.Items, "'items' array should be empty, not nil (e.g. `[]` not `null`)")
+	assert.Len(t, page.Items, 0, "There should be 0 items in the list")
 }
 
-// TestItemCreate_SingleItem tests POST /item/create/:item for a single item.
+// TestItemCreate_SingleItem tests POST /items with a JSON body.
 func TestItemCreate_SingleItem(t *testing.T) {
-	if db == nil {
-		t.Fatal("Database connection (db) is nil.")
-	}
 	emptyTable()
 
 	itemName := "TestItem1"
-	// API Handler Requirement: Create handler should take item name, save it, DB assigns ID.
-	// Handler should return the created item, including its new ID.
-	// The original test expected response key "items" for a single item. Adjust if your API returns differently (e.g. "item").
-	w := performRequest(router, "GET", fmt.Sprintf("/item/create/%s", itemName)) // Using GET as per original routes
+	w := performJSONRequest(router, "POST", "/items", CreateItemRequest{Item: itemName})
 	assert.Equal(t, http.StatusCreated, w.Code, "Expected HTTP 201 Created")
 
-	var responseBody map[string]ListItem // Assuming response is `{"item": ListItem}` or `{"items": ListItem}`
+	var responseBody map[string]ListItem
 	err := json.Unmarshal(w.Body.Bytes(), &responseBody)
 	assert.Nil(t, err, "JSON unmarshalling should succeed")
-	if err != nil {
-		t.Logf("Response body was: %s", w.Body.String())
-	}
-
-	// Adjust key if your API returns {"item": ...} instead of {"items": ...} for single create
-	createdItem, exists := responseBody["item"] // Changed from "items" to "item" for typical single resource response
-	if !exists {                                // Fallback if original "items" key is used
-		createdItem, exists = responseBody["items"]
-	}
-	assert.True(t, exists, "Response should contain the created item under 'item' or 'items' key")
 
+	createdItem, exists := responseBody["item"]
+	assert.True(t, exists, "Response should contain the created item under 'item'")
 	assert.Equal(t, itemName, createdItem.Item, "Created item's name should match")
 	assert.False(t, createdItem.Done, "Newly created item should not be done")
 	assert.NotEmpty(t, createdItem.Id, "Created item should have a non-empty ID from the database")
 }
 
+// TestItemCreate_MissingBody tests POST /items without an "item" field.
+func TestItemCreate_MissingBody(t *testing.T) {
+	emptyTable()
+
+	w := performJSONRequest(router, "POST", "/items", map[string]string{})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Expected HTTP 400 Bad Request")
+}
+
+// TestItemCreate_WrongContentType tests POST /items with a non-JSON
+// Content-Type, which should be rejected before the body is even parsed.
+func TestItemCreate_WrongContentType(t *testing.T) {
+	emptyTable()
+
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBufferString(`{"item":"TestItem1"}`))
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Expected HTTP 400 Bad Request for a non-JSON Content-Type")
+}
+
 // TestItemsCreate_MultipleItems tests creating multiple items and then listing them.
 func TestItemsCreate_MultipleItems(t *testing.T) {
-	if db == nil {
-		t.Fatal("Database connection (db) is nil.")
-	}
 	emptyTable()
 
 	item1Name := "MultiTest1"
 	item2Name := "MultiTest2"
 
-	wCreate1 := performRequest(router, "GET", fmt.Sprintf("/item/create/%s", item1Name))
+	wCreate1 := performJSONRequest(router, "POST", "/items", CreateItemRequest{Item: item1Name})
 	assert.Equal(t, http.StatusCreated, wCreate1.Code)
-	// Optionally unmarshal and check wCreate1.Body if needed
 
-	wCreate2 := performRequest(router, "GET", fmt.Sprintf("/item/create/%s", item2Name))
+	wCreate2 := performJSONRequest(router, "POST", "/items", CreateItemRequest{Item: item2Name})
 	assert.Equal(t, http.StatusCreated, wCreate2.Code)
-	// Optionally unmarshal and check wCreate2.Body if needed
 
 	wList := performRequest(router, "GET", "/items")
 	assert.Equal(t, http.StatusOK, wList.Code)
 
-	var listResponseBody map[string][]ListItem
-	err := json.Unmarshal(wList.Body.Bytes(), &listResponseBody)
+	var page ItemsPage
+	err := json.Unmarshal(wList.Body.Bytes(), &page)
 	assert.Nil(t, err)
+	assert.Len(t, page.Items, 2, "Should be 2 items in the list")
 
-	listedItems, exists := listResponseBody["items"]
-	assert.True(t, exists)
-	assert.Len(t, listedItems, 2, "Should be 2 items in the list")
-
-	// Check if items exist (order might not be guaranteed unless API sorts)
-	foundItem1 := false
-	foundItem2 := false
-	for _, item := range listedItems {
+	foundItem1, foundItem2 := false, false
+	for _, item := range page.Items {
 		if item.Item == item1Name {
 			foundItem1 = true
 			assert.NotEmpty(t, item.Id)
@@ -309,126 +204,274 @@ func TestItemsCreate_MultipleItems(t *testing.T) {
 	assert.True(t, foundItem2, "Item 2 should be in the list")
 }
 
-// TestItemDelete_ExistingItem tests deleting an existing item.
-func TestItemDelete_ExistingItem(t *testing.T) {
-	if db == nil {
-		t.Fatal("Database connection (db) is nil.")
+// TestItemsGet_Pagination tests GET /items?limit=&cursor= paging through results.
+func TestItemsGet_Pagination(t *testing.T) {
+	emptyTable()
+
+	for _, name := range []string{"Page1", "Page2", "Page3"} {
+		w := performJSONRequest(router, "POST", "/items", CreateItemRequest{Item: name})
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	wFirst := performRequest(router, "GET", "/items?limit=2")
+	assert.Equal(t, http.StatusOK, wFirst.Code)
+
+	var firstPage ItemsPage
+	assert.Nil(t, json.Unmarshal(wFirst.Body.Bytes(), &firstPage))
+	assert.Len(t, firstPage.Items, 2)
+	assert.NotEmpty(t, firstPage.NextCursor, "a further page should set next_cursor")
+	assert.Equal(t, "Page1", firstPage.Items[0].Item, "items should be ordered chronologically")
+	assert.Equal(t, "Page2", firstPage.Items[1].Item, "items should be ordered chronologically")
+
+	wSecond := performRequest(router, "GET", fmt.Sprintf("/items?limit=2&cursor=%s", firstPage.NextCursor))
+	assert.Equal(t, http.StatusOK, wSecond.Code)
+
+	var secondPage ItemsPage
+	assert.Nil(t, json.Unmarshal(wSecond.Body.Bytes(), &secondPage))
+	assert.Len(t, secondPage.Items, 1)
+	assert.Equal(t, "Page3", secondPage.Items[0].Item, "the cursor should resume right after the prior page")
+	assert.Empty(t, secondPage.NextCursor, "the last page should not set next_cursor")
+}
+
+// TestItemsGet_DefaultLimit tests that GET /items with no `limit` still
+// paginates, rather than returning the whole table.
+func TestItemsGet_DefaultLimit(t *testing.T) {
+	emptyTable()
+
+	for i := 0; i < defaultPageSize+1; i++ {
+		w := performJSONRequest(router, "POST", "/items", CreateItemRequest{Item: fmt.Sprintf("Item%d", i)})
+		assert.Equal(t, http.StatusCreated, w.Code)
 	}
+
+	w := performRequest(router, "GET", "/items")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var page ItemsPage
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Items, defaultPageSize, "should be capped at defaultPageSize when limit is omitted")
+	assert.NotEmpty(t, page.NextCursor, "a further page should set next_cursor")
+}
+
+// TestItemsGet_DoneFilter tests GET /items?done= filtering.
+func TestItemsGet_DoneFilter(t *testing.T) {
+	emptyTable()
+
+	wCreate := performJSONRequest(router, "POST", "/items", CreateItemRequest{Item: "FilterMe"})
+	assert.Equal(t, http.StatusCreated, wCreate.Code)
+	var created map[string]ListItem
+	assert.Nil(t, json.Unmarshal(wCreate.Body.Bytes(), &created))
+
+	wUpdate := performJSONRequest(router, "PATCH", fmt.Sprintf("/items/%s", created["item"].Id), UpdateItemRequest{Done: boolPtr(true)})
+	assert.Equal(t, http.StatusOK, wUpdate.Code)
+
+	wDone := performRequest(router, "GET", "/items?done=true")
+	assert.Equal(t, http.StatusOK, wDone.Code)
+	var donePage ItemsPage
+	assert.Nil(t, json.Unmarshal(wDone.Body.Bytes(), &donePage))
+	assert.Len(t, donePage.Items, 1)
+
+	wNotDone := performRequest(router, "GET", "/items?done=false")
+	assert.Equal(t, http.StatusOK, wNotDone.Code)
+	var notDonePage ItemsPage
+	assert.Nil(t, json.Unmarshal(wNotDone.Body.Bytes(), &notDonePage))
+	assert.Len(t, notDonePage.Items, 0)
+}
+
+// TestItemDelete_ExistingItem tests DELETE /items/:id.
+func TestItemDelete_ExistingItem(t *testing.T) {
 	emptyTable()
 
-	// Create an item to delete and one to keep
-	performRequest(router, "GET", "/item/create/ToDelete") // Will get ID "1" (assuming sequence reset)
-	performRequest(router, "GET", "/item/create/ToKeep")   // Will get ID "2"
+	wCreate := performJSONRequest(router, "POST", "/items", CreateItemRequest{Item: "ToDelete"})
+	var created map[string]ListItem
+	assert.Nil(t, json.Unmarshal(wCreate.Body.Bytes(), &created))
+	performJSONRequest(router, "POST", "/items", CreateItemRequest{Item: "ToKeep"})
 
-	// Delete item with ID "1"
-	// API Handler Requirement: Successful delete should return 200 OK or 204 No Content.
-	// If it returns a body, it might be `{"message": "deleted"}`.
-	wDelete := performRequest(router, "GET", "/item/delete/1") // ID "1" is assumed
-	assert.Contains(t, []int{http.StatusOK, http.StatusNoContent}, wDelete.Code, "Delete should return 200 OK or 204 No Content")
+	wDelete := performRequest(router, "DELETE", fmt.Sprintf("/items/%s", created["item"].Id))
+	assert.Equal(t, http.StatusOK, wDelete.Code)
 
-	// Verify it's deleted by trying to list items
 	wList := performRequest(router, "GET", "/items")
 	assert.Equal(t, http.StatusOK, wList.Code)
 
-	var listResponseBody map[string][]ListItem
-	err := json.Unmarshal(wList.Body.Bytes(), &listResponseBody)
-	assert.Nil(t, err)
-
-	listedItems, exists := listResponseBody["items"]
-	assert.True(t, exists)
-	assert.Len(t, listedItems, 1, "Only one item should remain")
-	if len(listedItems) == 1 {
-		assert.Equal(t, "ToKeep", listedItems[0].Item)
-		// assert.Equal(t, "2", listedItems[0].Id) // ID check can be tricky if not predictable
+	var page ItemsPage
+	assert.Nil(t, json.Unmarshal(wList.Body.Bytes(), &page))
+	assert.Len(t, page.Items, 1, "Only one item should remain")
+	if len(page.Items) == 1 {
+		assert.Equal(t, "ToKeep", page.Items[0].Item)
 	}
 }
 
 // TestItemDelete_NotExistingItem tests deleting a non-existent item.
 func TestItemDelete_NotExistingItem(t *testing.T) {
-	if db == nil {
-		t.Fatal("Database connection (db) is nil.")
-	}
 	emptyTable()
 
-	// API Handler Requirement: Deleting non-existent item should return 404 Not Found
-	// with a body like `{"message": "not found"}`.
-	w := performRequest(router, "GET", "/item/delete/999") // ID 999 assumed not to exist
+	w := performRequest(router, "DELETE", "/items/999")
 	assert.Equal(t, http.StatusNotFound, w.Code, "Expected HTTP 404 Not Found")
 
 	var responseBody map[string]string
 	err := json.Unmarshal(w.Body.Bytes(), &responseBody)
 	assert.Nil(t, err, "JSON unmarshalling for error message should succeed")
-	if err != nil {
-		t.Logf("Response body was: %s", w.Body.String())
-	}
-
-	message, exists := responseBody["message"]
-	assert.True(t, exists, "Error response should contain 'message' key")
-	assert.Equal(t, "not found", message, "Error message should be 'not found'")
+	assert.Equal(t, "not found", responseBody["message"])
 }
 
-// TestItemUpdate_ExistingItem tests updating an existing item.
+// TestItemUpdate_ExistingItem tests PATCH /items/:id with a JSON body.
 func TestItemUpdate_ExistingItem(t *testing.T) {
-	if db == nil {
-		t.Fatal("Database connection (db) is nil.")
-	}
 	emptyTable()
 
-	// Create an item
-	wCreate := performRequest(router, "GET", "/item/create/ToUpdate")
+	wCreate := performJSONRequest(router, "POST", "/items", CreateItemRequest{Item: "ToUpdate"})
 	assert.Equal(t, http.StatusCreated, wCreate.Code)
-	var createRespBody map[string]ListItem
-	_ = json.Unmarshal(wCreate.Body.Bytes(), &createRespBody)
-	// createdID := createRespBody["item"].Id // Assuming response is `{"item": ...}` and contains ID
-	// For simplicity, assuming first created item gets ID "1" due to emptyTable()
-	createdID := "1"
-
-	// API Handler Requirement: Update should change the item's 'done' status.
-	// Should return 200 OK, possibly with the updated item.
-	wUpdate := performRequest(router, "GET", fmt.Sprintf("/item/update/%s/true", createdID))
-	assert.Equal(t, http.StatusOK, wUpdate.Code, "Expected HTTP 200 OK for update")
+	var created map[string]ListItem
+	assert.Nil(t, json.Unmarshal(wCreate.Body.Bytes(), &created))
+	createdID := created["item"].Id
 
-	// Verify by fetching the item or listing all items
-	wList := performRequest(router, "GET", "/items")
-	assert.Equal(t, http.StatusOK, wList.Code)
-
-	var listResponseBody map[string][]ListItem
-	err := json.Unmarshal(wList.Body.Bytes(), &listResponseBody)
-	assert.Nil(t, err)
+	wUpdate := performJSONRequest(router, "PATCH", fmt.Sprintf("/items/%s", createdID), UpdateItemRequest{Done: boolPtr(true)})
+	assert.Equal(t, http.StatusOK, wUpdate.Code, "Expected HTTP 200 OK for update")
 
-	listedItems, exists := listResponseBody["items"]
-	assert.True(t, exists)
-	foundUpdated := false
-	for _, item := range listedItems {
-		if item.Id == createdID {
-			assert.Equal(t, "ToUpdate", item.Item)
-			assert.True(t, item.Done, "Item should be marked as done after update")
-			foundUpdated = true
-			break
-		}
-	}
-	assert.True(t, foundUpdated, "Updated item should be found in the list")
+	var updated map[string]ListItem
+	assert.Nil(t, json.Unmarshal(wUpdate.Body.Bytes(), &updated))
+	assert.Equal(t, "ToUpdate", updated["item"].Item)
+	assert.True(t, updated["item"].Done, "Item should be marked as done after update")
 }
 
 // TestItemUpdate_NotExistingItem tests updating a non-existent item.
 func TestItemUpdate_NotExistingItem(t *testing.T) {
-	if db == nil {
-		t.Fatal("Database connection (db) is nil.")
-	}
 	emptyTable()
 
-	// API Handler Requirement: Updating non-existent item should return 404 Not Found
-	// with a body like `{"message": "not found"}`.
-	w := performRequest(router, "GET", "/item/update/999/true") // ID 999 assumed not to exist
+	w := performJSONRequest(router, "PATCH", "/items/999", UpdateItemRequest{Done: boolPtr(true)})
 	assert.Equal(t, http.StatusNotFound, w.Code, "Expected HTTP 404 Not Found")
 
 	var responseBody map[string]string
 	err := json.Unmarshal(w.Body.Bytes(), &responseBody)
 	assert.Nil(t, err, "JSON unmarshalling for error message should succeed")
-	if err != nil {
-		t.Logf("Response body was: %s", w.Body.String())
+	assert.Equal(t, "not found", responseBody["message"])
+}
+
+// TestDeprecatedRoutes_StillWork exercises the old GET-based aliases to make
+// sure they keep working for clients that haven't migrated yet.
+func TestDeprecatedRoutes_StillWork(t *testing.T) {
+	emptyTable()
+
+	wCreate := performRequest(router, "GET", "/item/create/Legacy")
+	assert.Equal(t, http.StatusCreated, wCreate.Code)
+	var created map[string]ListItem
+	assert.Nil(t, json.Unmarshal(wCreate.Body.Bytes(), &created))
+	createdID := created["item"].Id
+
+	wUpdate := performRequest(router, "GET", fmt.Sprintf("/item/update/%s/true", createdID))
+	assert.Equal(t, http.StatusOK, wUpdate.Code)
+
+	wDelete := performRequest(router, "GET", fmt.Sprintf("/item/delete/%s", createdID))
+	assert.Equal(t, http.StatusOK, wDelete.Code)
+}
+
+// TestItemsGet_ScopedToOwner ensures one user can't see another user's items.
+func TestItemsGet_ScopedToOwner(t *testing.T) {
+	emptyTable()
+
+	w := performJSONRequest(router, "POST", "/items", CreateItemRequest{Item: "Mine"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	otherToken := authtest.NewToken(t, uuid.NewString())
+	req, _ := http.NewRequest("GET", "/items", nil)
+	req.Header.Set("Authorization", "Bearer "+otherToken)
+	otherW := httptest.NewRecorder()
+	router.ServeHTTP(otherW, req)
+
+	assert.Equal(t, http.StatusOK, otherW.Code)
+	var page ItemsPage
+	assert.Nil(t, json.Unmarshal(otherW.Body.Bytes(), &page))
+	assert.Len(t, page.Items, 0, "a different user should not see this user's items")
+}
+
+// TestItemEvents_StreamsCreateEvent opens a streaming request to
+// GET /items/events and asserts a created item's event arrives on it.
+func TestItemEvents_StreamsCreateEvent(t *testing.T) {
+	emptyTable()
+
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	req, _ := http.NewRequest("GET", ts.URL+"/items/events", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+
+	dataCh := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				dataCh <- data
+				return
+			}
+		}
+	}()
+
+	time.Sleep(50 * time.Millisecond) // give the subscription time to register
+
+	w := performJSONRequest(router, "POST", "/items", CreateItemRequest{Item: "Streamed"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	select {
+	case data := <-dataCh:
+		var evt Event
+		assert.Nil(t, json.Unmarshal([]byte(data), &evt))
+		assert.Equal(t, EventItemCreated, evt.Type)
+		assert.Equal(t, "Streamed", evt.Item.Item)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for SSE event")
 	}
-	message, exists := responseBody["message"]
-	assert.True(t, exists, "Error response should contain 'message' key")
-	assert.Equal(t, "not found", message, "Error message should be 'not found'")
 }
+
+// TestItemEvents_Replay tests that `?since=` replays buffered events that
+// were published before the subscriber connected.
+func TestItemEvents_Replay(t *testing.T) {
+	emptyTable()
+
+	// A dedicated user keeps this test's replay isolated from events
+	// published by other tests sharing the package-level event buffer.
+	replayToken := authtest.NewToken(t, uuid.NewString())
+
+	createReq, _ := http.NewRequest("POST", "/items", bytes.NewBufferString(`{"item":"Before"}`))
+	createReq.Header.Set("Content-Type", "application/json")
+	createReq.Header.Set("Authorization", "Bearer "+replayToken)
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReq)
+	assert.Equal(t, http.StatusCreated, createW.Code)
+
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	req, _ := http.NewRequest("GET", ts.URL+"/items/events?since=0", nil)
+	req.Header.Set("Authorization", "Bearer "+replayToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+
+	dataCh := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				dataCh <- data
+				return
+			}
+		}
+	}()
+
+	select {
+	case data := <-dataCh:
+		var evt Event
+		assert.Nil(t, json.Unmarshal([]byte(data), &evt))
+		assert.Equal(t, EventItemCreated, evt.Type)
+		assert.Equal(t, "Before", evt.Item.Item)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for replayed SSE event")
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
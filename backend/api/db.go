@@ -0,0 +1,66 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/el10savio/TODO-Fullstack-App-Go-Gin-Postgres-React/backend/migrations"
+	_ "github.com/lib/pq"
+)
+
+// dsn builds the Postgres connection string from the environment so the
+// same binary works locally and in CI.
+func dsn() string {
+	dbHost := os.Getenv("DB_HOST")
+	dbPort := os.Getenv("DB_PORT")
+	dbUser := os.Getenv("DB_USER")
+	dbPassword := os.Getenv("DB_PASSWORD")
+	dbName := os.Getenv("DB_NAME")
+
+	if dbHost == "" {
+		dbHost = "localhost"
+	}
+	if dbPort == "" {
+		dbPort = "5432"
+	}
+
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		dbHost, dbPort, dbUser, dbPassword, dbName)
+}
+
+// Connect opens the Postgres connection used by the API handlers, without
+// running migrations. It is exported so the `migrate` CLI subcommand can
+// reuse the same connection parameters.
+func Connect() (*sql.DB, error) {
+	conn, err := sql.Open("postgres", dsn())
+	if err != nil {
+		return nil, fmt.Errorf("api: failed to open db connection: %w", err)
+	}
+	if err := conn.Ping(); err != nil {
+		return nil, fmt.Errorf("api: failed to ping db: %w", err)
+	}
+	return conn, nil
+}
+
+// SetupPostgres opens the Postgres connection backing the API and runs any
+// pending migrations before the server starts serving traffic. It returns
+// the connection so callers can build a PostgresRepository from it and
+// share the pool with other packages (e.g. auth).
+func SetupPostgres() *sql.DB {
+	db, err := Connect()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		log.Fatalf("api: failed to load migrations: %v", err)
+	}
+	if err := migrator.Up(); err != nil {
+		log.Fatalf("api: failed to run migrations: %v", err)
+	}
+
+	return db
+}
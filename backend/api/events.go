@@ -0,0 +1,102 @@
+package api
+
+import "sync"
+
+// EventType identifies the kind of mutation a published Event represents.
+type EventType string
+
+const (
+	EventItemCreated EventType = "item.created"
+	EventItemUpdated EventType = "item.updated"
+	EventItemDeleted EventType = "item.deleted"
+)
+
+// Event is a single todo item mutation, as delivered over the SSE stream.
+// OwnerID is not serialized; it exists so subscribers can be filtered to
+// the events their user is allowed to see.
+type Event struct {
+	ID      uint64    `json:"id"`
+	Type    EventType `json:"type"`
+	Item    ListItem  `json:"item"`
+	OwnerID string    `json:"-"`
+}
+
+// eventBufferSize bounds how many past events a late subscriber can replay.
+const eventBufferSize = 256
+
+// EventBus is an in-process publish/subscribe hub for todo item mutations.
+// It keeps a bounded ring buffer of recent events, keyed by monotonically
+// increasing event IDs, so a subscriber that reconnects with `?since=` can
+// catch up on what it missed.
+type EventBus struct {
+	mu          sync.Mutex
+	nextID      uint64
+	buffer      []Event
+	subscribers map[chan Event]struct{}
+}
+
+// NewEventBus returns an empty EventBus.
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[chan Event]struct{})}
+}
+
+// events is the package-level bus every handler publishes mutations to.
+var events = NewEventBus()
+
+// Publish records a new event and fans it out to every current subscriber.
+// Subscribers that aren't keeping up are skipped rather than blocking the
+// publisher.
+func (b *EventBus) Publish(eventType EventType, ownerID string, item ListItem) Event {
+	b.mu.Lock()
+	b.nextID++
+	event := Event{ID: b.nextID, Type: eventType, Item: item, OwnerID: ownerID}
+
+	b.buffer = append(b.buffer, event)
+	if len(b.buffer) > eventBufferSize {
+		b.buffer = b.buffer[len(b.buffer)-eventBufferSize:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub <- event:
+		default:
+		}
+	}
+	b.mu.Unlock()
+
+	return event
+}
+
+// Subscribe registers a new subscriber channel and returns it along with an
+// unsubscribe function the caller must call when done listening.
+func (b *EventBus) Subscribe() (<-chan Event, func()) {
+	sub := make(chan Event, 16)
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		delete(b.subscribers, sub)
+		b.mu.Unlock()
+		close(sub)
+	}
+
+	return sub, unsubscribe
+}
+
+// Since returns every buffered event with an ID greater than since, in
+// order. Events older than the buffer's capacity are no longer available.
+func (b *EventBus) Since(since uint64) []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	replay := make([]Event, 0, len(b.buffer))
+	for _, event := range b.buffer {
+		if event.ID > since {
+			replay = append(replay, event)
+		}
+	}
+	return replay
+}
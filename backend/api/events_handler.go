@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ItemEvents handles GET /items/events, streaming the authenticated user's
+// item mutations as Server-Sent Events. If `since` is set, buffered events
+// with a greater ID are replayed before new events start flowing; if it is
+// omitted, the stream starts empty and only carries events published after
+// the subscriber connects.
+func (a *API) ItemEvents(c *gin.Context) {
+	ownerID := c.GetString("user_id")
+
+	hasSince := c.Query("since") != ""
+	since := uint64(0)
+	if hasSince {
+		parsed, err := strconv.ParseUint(c.Query("since"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "invalid since"})
+			return
+		}
+		since = parsed
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+
+	sub, unsubscribe := events.Subscribe()
+	defer unsubscribe()
+
+	flusher, canFlush := c.Writer.(http.Flusher)
+
+	// lastReplayedID tracks the newest event id written during replay, so
+	// that event is not then delivered a second time from sub: Subscribe
+	// registers the live channel before the replay snapshot is taken, so
+	// anything published in between lands in both.
+	lastReplayedID := since
+	if hasSince {
+		for _, event := range events.Since(since) {
+			if event.OwnerID != ownerID {
+				continue
+			}
+			writeEvent(c.Writer, event)
+			if event.ID > lastReplayedID {
+				lastReplayedID = event.ID
+			}
+		}
+	}
+	// Flush even with nothing replayed, so the client receives the response
+	// headers as soon as the stream is open.
+	if canFlush {
+		flusher.Flush()
+	}
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case event, ok := <-sub:
+			if !ok {
+				return
+			}
+			if event.OwnerID != ownerID {
+				continue
+			}
+			if hasSince && event.ID <= lastReplayedID {
+				continue
+			}
+			writeEvent(c.Writer, event)
+			if canFlush {
+				flusher.Flush()
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// writeEvent encodes event as a single SSE message.
+func writeEvent(w http.ResponseWriter, event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, payload)
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultPageSize is the number of items TodoItems returns when the caller
+// omits `limit`, so a plain GET /items can't accidentally return an
+// unbounded table scan.
+const defaultPageSize = 20
+
+// API holds the handler dependencies, primarily the Repository used to
+// read and write todo items.
+type API struct {
+	repo Repository
+}
+
+// NewAPI constructs an API backed by repo.
+func NewAPI(repo Repository) *API {
+	return &API{repo: repo}
+}
+
+// Readyz handles GET /readyz. It reports ready only if the backing
+// Repository can be reached, so a load balancer can stop routing traffic to
+// an instance that's lost its database.
+func (a *API) Readyz(c *gin.Context) {
+	if err := a.repo.Ping(c.Request.Context()); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready", "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
+
+// TodoItems handles GET /items. It lists the authenticated user's todo
+// items, optionally filtered by `done` and paginated via `limit`/`cursor`,
+// where cursor is the id of the last item seen on the previous page.
+// `limit` defaults to defaultPageSize when omitted.
+func (a *API) TodoItems(c *gin.Context) {
+	filter := ListFilter{OwnerID: c.GetString("user_id"), Cursor: c.Query("cursor"), Limit: defaultPageSize}
+
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "invalid limit"})
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	if raw := c.Query("done"); raw != "" {
+		done, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "invalid done filter"})
+			return
+		}
+		filter.Done = &done
+	}
+
+	items, err := a.repo.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page := ItemsPage{Items: items}
+	if filter.Limit > 0 && len(items) > filter.Limit {
+		page.Items = items[:filter.Limit]
+		page.NextCursor = page.Items[filter.Limit-1].Id
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// requireJSONBody rejects requests that don't declare a JSON Content-Type
+// before binding dst, writing a 400 response and returning false on either
+// failure.
+func requireJSONBody(c *gin.Context, dst interface{}) bool {
+	if c.ContentType() != "application/json" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Content-Type must be application/json"})
+		return false
+	}
+	if err := c.ShouldBindJSON(dst); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return false
+	}
+	return true
+}
+
+// CreateItem handles POST /items with a JSON body of {"item": "..."},
+// owned by the authenticated user.
+func (a *API) CreateItem(c *gin.Context) {
+	var req CreateItemRequest
+	if !requireJSONBody(c, &req) {
+		return
+	}
+
+	ownerID := c.GetString("user_id")
+	item, err := a.repo.Create(c.Request.Context(), ownerID, req.Item)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	events.Publish(EventItemCreated, ownerID, item)
+	c.JSON(http.StatusCreated, gin.H{"item": item})
+}
+
+// UpdateItem handles PATCH /items/:id with a JSON body of
+// {"item": "...", "done": bool}; either field may be omitted, but not both.
+// Only an item owned by the authenticated user can be updated.
+func (a *API) UpdateItem(c *gin.Context) {
+	ownerID := c.GetString("user_id")
+
+	var req UpdateItemRequest
+	if !requireJSONBody(c, &req) {
+		return
+	}
+
+	if req.Item == nil && req.Done == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "nothing to update"})
+		return
+	}
+
+	item, err := a.repo.Update(c.Request.Context(), ownerID, c.Param("id"), req.Item, req.Done)
+	if err != nil {
+		if err == ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	events.Publish(EventItemUpdated, ownerID, item)
+	c.JSON(http.StatusOK, gin.H{"item": item})
+}
+
+// DeleteItem handles DELETE /items/:id. Only an item owned by the
+// authenticated user can be deleted.
+func (a *API) DeleteItem(c *gin.Context) {
+	ownerID := c.GetString("user_id")
+
+	item, err := a.repo.Delete(c.Request.Context(), ownerID, c.Param("id"))
+	if err != nil {
+		if err == ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	events.Publish(EventItemDeleted, ownerID, item)
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// CreateTodoItem is the deprecated GET /item/create/:item handler, kept as
+// an alias for existing clients; it stays registered unless
+// DISABLE_DEPRECATED_ROUTES=true is set.
+func (a *API) CreateTodoItem(c *gin.Context) {
+	ownerID := c.GetString("user_id")
+
+	item, err := a.repo.Create(c.Request.Context(), ownerID, c.Param("item"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	events.Publish(EventItemCreated, ownerID, item)
+	c.JSON(http.StatusCreated, gin.H{"item": item})
+}
+
+// UpdateTodoItem is the deprecated GET /item/update/:id/:done handler, kept
+// as an alias for existing clients; it stays registered unless
+// DISABLE_DEPRECATED_ROUTES=true is set.
+func (a *API) UpdateTodoItem(c *gin.Context) {
+	ownerID := c.GetString("user_id")
+
+	done, err := strconv.ParseBool(c.Param("done"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid done value"})
+		return
+	}
+
+	item, err := a.repo.Update(c.Request.Context(), ownerID, c.Param("id"), nil, &done)
+	if err != nil {
+		if err == ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	events.Publish(EventItemUpdated, ownerID, item)
+	c.JSON(http.StatusOK, gin.H{"item": item})
+}
+
+// DeleteTodoItem is the deprecated GET /item/delete/:id handler, kept as an
+// alias for existing clients; it stays registered unless
+// DISABLE_DEPRECATED_ROUTES=true is set.
+func (a *API) DeleteTodoItem(c *gin.Context) {
+	ownerID := c.GetString("user_id")
+
+	item, err := a.repo.Delete(c.Request.Context(), ownerID, c.Param("id"))
+	if err != nil {
+		if err == ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	events.Publish(EventItemDeleted, ownerID, item)
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
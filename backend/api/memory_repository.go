@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MemoryRepository is an in-memory Repository, used by SetupRoutesForTest
+// so the handler test suite runs hermetically without a live Postgres.
+type MemoryRepository struct {
+	mu    sync.Mutex
+	order []string
+	items map[string]ListItem
+	owner map[string]string
+}
+
+// NewMemoryRepository returns an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		items: make(map[string]ListItem),
+		owner: make(map[string]string),
+	}
+}
+
+func (r *MemoryRepository) List(ctx context.Context, filter ListFilter) ([]ListItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	started := filter.Cursor == ""
+	items := make([]ListItem, 0)
+	for _, id := range r.order {
+		if !started {
+			if id == filter.Cursor {
+				started = true
+			}
+			continue
+		}
+
+		if r.owner[id] != filter.OwnerID {
+			continue
+		}
+
+		item := r.items[id]
+		if filter.Done != nil && item.Done != *filter.Done {
+			continue
+		}
+
+		items = append(items, item)
+		if filter.Limit > 0 && len(items) > filter.Limit {
+			break
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, ownerID, item string) (ListItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := ListItem{Id: uuid.NewString(), Item: item}
+	r.items[created.Id] = created
+	r.owner[created.Id] = ownerID
+	r.order = append(r.order, created.Id)
+	return created, nil
+}
+
+func (r *MemoryRepository) Update(ctx context.Context, ownerID, id string, item *string, done *bool) (ListItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.items[id]
+	if !ok || r.owner[id] != ownerID {
+		return ListItem{}, ErrNotFound
+	}
+
+	if item != nil {
+		existing.Item = *item
+	}
+	if done != nil {
+		existing.Done = *done
+	}
+	r.items[id] = existing
+	return existing, nil
+}
+
+// Ping always succeeds; MemoryRepository has no external dependency to lose.
+func (r *MemoryRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, ownerID, id string) (ListItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.items[id]
+	if !ok || r.owner[id] != ownerID {
+		return ListItem{}, ErrNotFound
+	}
+
+	delete(r.items, id)
+	delete(r.owner, id)
+	for i, orderedID := range r.order {
+		if orderedID == id {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	return existing, nil
+}
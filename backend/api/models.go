@@ -0,0 +1,27 @@
+package api
+
+// ListItem represents a single todo item stored in the `list` table.
+type ListItem struct {
+	Id   string `json:"id"`
+	Item string `json:"item"`
+	Done bool   `json:"done"`
+}
+
+// CreateItemRequest is the JSON body accepted by POST /items.
+type CreateItemRequest struct {
+	Item string `json:"item" binding:"required"`
+}
+
+// UpdateItemRequest is the JSON body accepted by PATCH /items/:id.
+// Item and Done are pointers so a caller can update either field
+// independently without clobbering the other with a zero value.
+type UpdateItemRequest struct {
+	Item *string `json:"item"`
+	Done *bool   `json:"done"`
+}
+
+// ItemsPage is the response body for GET /items, cursor-paginated.
+type ItemsPage struct {
+	Items      []ListItem `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+)
+
+// tracer is shared by every PostgresRepository method so each SQL call
+// nests as a child span under the request span started by tracing.Middleware.
+var tracer = otel.Tracer("todo-app/api")
+
+// dbQueryDuration tracks how long each repository SQL call takes, labeled by
+// the logical operation so slow queries show up per-method in Grafana.
+var dbQueryDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of repository SQL calls",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{"operation"},
+)
+
+func init() {
+	prometheus.MustRegister(dbQueryDuration)
+}
+
+// observeQuery records how long operation took since start.
+func observeQuery(operation string, start time.Time) {
+	dbQueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// PostgresRepository is the Repository backed by the `list` table.
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository returns a Repository backed by db.
+func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) List(ctx context.Context, filter ListFilter) ([]ListItem, error) {
+	ctx, span := tracer.Start(ctx, "PostgresRepository.List")
+	defer span.End()
+	defer observeQuery("list", time.Now())
+
+	query := "SELECT id, item, done FROM list WHERE owner_id = $1"
+	args := []interface{}{filter.OwnerID}
+
+	if filter.Cursor != "" {
+		args = append(args, filter.Cursor)
+		query += " AND (created_at, id) > (SELECT created_at, id FROM list WHERE id = $" + strconv.Itoa(len(args)) + ")"
+	}
+
+	if filter.Done != nil {
+		args = append(args, *filter.Done)
+		query += " AND done = $" + strconv.Itoa(len(args))
+	}
+
+	// id's a random UUID, so order by the chronological created_at column
+	// instead; id only breaks ties between rows created in the same instant,
+	// matching MemoryRepository's insertion-order iteration.
+	query += " ORDER BY created_at, id"
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit+1)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]ListItem, 0)
+	for rows.Next() {
+		var item ListItem
+		if err := rows.Scan(&item.Id, &item.Item, &item.Done); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, ownerID, item string) (ListItem, error) {
+	ctx, span := tracer.Start(ctx, "PostgresRepository.Create")
+	defer span.End()
+	defer observeQuery("create", time.Now())
+
+	created := ListItem{Id: uuid.NewString(), Item: item}
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO list (id, item, done, owner_id) VALUES ($1, $2, false, $3)",
+		created.Id, created.Item, ownerID,
+	)
+	return created, err
+}
+
+func (r *PostgresRepository) Update(ctx context.Context, ownerID, id string, item *string, done *bool) (ListItem, error) {
+	ctx, span := tracer.Start(ctx, "PostgresRepository.Update")
+	defer span.End()
+	defer observeQuery("update", time.Now())
+
+	var updated ListItem
+	row := r.db.QueryRowContext(ctx,
+		"UPDATE list SET item = COALESCE($1, item), done = COALESCE($2, done) WHERE id = $3 AND owner_id = $4 RETURNING id, item, done",
+		item, done, id, ownerID,
+	)
+	if err := row.Scan(&updated.Id, &updated.Item, &updated.Done); err != nil {
+		if err == sql.ErrNoRows {
+			return ListItem{}, ErrNotFound
+		}
+		return ListItem{}, err
+	}
+	return updated, nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, ownerID, id string) (ListItem, error) {
+	ctx, span := tracer.Start(ctx, "PostgresRepository.Delete")
+	defer span.End()
+	defer observeQuery("delete", time.Now())
+
+	deleted := ListItem{Id: id}
+	row := r.db.QueryRowContext(ctx, "DELETE FROM list WHERE id = $1 AND owner_id = $2 RETURNING item, done", id, ownerID)
+	if err := row.Scan(&deleted.Item, &deleted.Done); err != nil {
+		if err == sql.ErrNoRows {
+			return ListItem{}, ErrNotFound
+		}
+		return ListItem{}, err
+	}
+	return deleted, nil
+}
+
+// Ping reports whether the database is reachable, used by the /readyz
+// handler to keep an instance that's lost its database out of rotation.
+func (r *PostgresRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
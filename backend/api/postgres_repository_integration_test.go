@@ -0,0 +1,69 @@
+//go:build integration
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPostgresRepository_CRUDAndPagination exercises PostgresRepository
+// against a live Postgres instance (see dsn() in db.go for the connection
+// env vars, and `migrations` for the schema it expects). It's gated behind
+// the `integration` build tag since it needs a real database:
+//
+//	go test -tags=integration ./api/...
+func TestPostgresRepository_CRUDAndPagination(t *testing.T) {
+	db, err := Connect()
+	if err != nil {
+		t.Skipf("skipping: cannot reach postgres: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := NewPostgresRepository(db)
+	ownerID := uuid.NewString()
+
+	var ids []string
+	for _, name := range []string{"Int1", "Int2", "Int3"} {
+		item, err := repo.Create(ctx, ownerID, name)
+		assert.Nil(t, err)
+		ids = append(ids, item.Id)
+	}
+	defer func() {
+		for _, id := range ids {
+			repo.Delete(ctx, ownerID, id)
+		}
+	}()
+
+	firstPage, err := repo.List(ctx, ListFilter{OwnerID: ownerID, Limit: 2})
+	assert.Nil(t, err)
+	assert.Len(t, firstPage, 3, "List returns filter.Limit+1 so callers can detect a further page")
+	assert.Equal(t, "Int1", firstPage[0].Item, "results should be ordered by created_at")
+	assert.Equal(t, "Int2", firstPage[1].Item, "results should be ordered by created_at")
+
+	secondPage, err := repo.List(ctx, ListFilter{OwnerID: ownerID, Cursor: firstPage[1].Id})
+	assert.Nil(t, err)
+	assert.Len(t, secondPage, 1)
+	assert.Equal(t, "Int3", secondPage[0].Item, "cursor should resume right after the given item")
+
+	otherOwnerPage, err := repo.List(ctx, ListFilter{OwnerID: uuid.NewString()})
+	assert.Nil(t, err)
+	assert.Len(t, otherOwnerPage, 0, "a different owner should not see these items")
+
+	done := true
+	updated, err := repo.Update(ctx, ownerID, ids[0], nil, &done)
+	assert.Nil(t, err)
+	assert.True(t, updated.Done)
+	assert.Equal(t, "Int1", updated.Item, "COALESCE should leave the item text untouched")
+
+	deleted, err := repo.Delete(ctx, ownerID, ids[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "Int1", deleted.Item)
+
+	_, err = repo.Update(ctx, ownerID, ids[0], nil, &done)
+	assert.Equal(t, ErrNotFound, err, "updating a deleted item should 404")
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by Repository.Update and Repository.Delete when
+// no item matches the given owner and id.
+var ErrNotFound = errors.New("api: item not found")
+
+// ListFilter narrows a Repository.List call.
+type ListFilter struct {
+	OwnerID string
+	Done    *bool
+	Cursor  string
+	Limit   int
+}
+
+// Repository is the storage abstraction behind the API handlers. A
+// PostgresRepository backs the running server; a MemoryRepository lets
+// tests exercise the handlers without a live database.
+type Repository interface {
+	// List returns items matching filter in chronological (creation) order,
+	// plus up to one extra item beyond filter.Limit so the caller can tell
+	// whether a further page exists.
+	List(ctx context.Context, filter ListFilter) ([]ListItem, error)
+	Create(ctx context.Context, ownerID, item string) (ListItem, error)
+	Update(ctx context.Context, ownerID, id string, item *string, done *bool) (ListItem, error)
+	Delete(ctx context.Context, ownerID, id string) (ListItem, error)
+
+	// Ping reports whether the backing store is reachable. It backs the
+	// /readyz healthcheck.
+	Ping(ctx context.Context) error
+}
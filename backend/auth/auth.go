@@ -0,0 +1,83 @@
+// Package auth issues and validates the JWTs used to authenticate API
+// requests, and provides the Gin middleware that enforces them.
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// tokenTTL is how long an issued token remains valid.
+const tokenTTL = 24 * time.Hour
+
+// ErrInvalidToken is returned by ParseToken when the token is malformed,
+// expired, or signed with the wrong key.
+var ErrInvalidToken = errors.New("auth: invalid token")
+
+// claims is the JWT payload; the user's id is carried as the subject.
+type claims struct {
+	jwt.RegisteredClaims
+}
+
+// jwtSecret reads the signing key from the environment on every call so
+// tests can set it with t.Setenv.
+func jwtSecret() []byte {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "dev-secret"
+	}
+	return []byte(secret)
+}
+
+// IssueToken mints a signed JWT for the given user id.
+func IssueToken(userID string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	})
+
+	signed, err := token.SignedString(jwtSecret())
+	if err != nil {
+		return "", fmt.Errorf("auth: failed to sign token: %w", err)
+	}
+	return signed, nil
+}
+
+// ParseToken validates token and returns the user id it was issued for.
+func ParseToken(token string) (string, error) {
+	parsed, err := jwt.ParseWithClaims(token, &claims{}, func(t *jwt.Token) (interface{}, error) {
+		return jwtSecret(), nil
+	})
+	if err != nil || !parsed.Valid {
+		return "", ErrInvalidToken
+	}
+
+	c, ok := parsed.Claims.(*claims)
+	if !ok || c.Subject == "" {
+		return "", ErrInvalidToken
+	}
+
+	return c.Subject, nil
+}
+
+// HashPassword hashes a plaintext password for storage.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("auth: failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches hash.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,21 @@
+// Package authtest provides test-only helpers for minting bearer tokens,
+// so other packages' test suites can exercise authenticated routes without
+// reimplementing JWT issuance.
+package authtest
+
+import (
+	"testing"
+
+	"github.com/el10savio/TODO-Fullstack-App-Go-Gin-Postgres-React/backend/auth"
+)
+
+// NewToken mints a bearer token for userID, failing t if signing errors.
+func NewToken(t testing.TB, userID string) string {
+	t.Helper()
+
+	token, err := auth.IssueToken(userID)
+	if err != nil {
+		t.Fatalf("authtest: failed to issue token: %v", err)
+	}
+	return token
+}
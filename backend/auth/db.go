@@ -0,0 +1,13 @@
+package auth
+
+import "database/sql"
+
+// db is the package-level Postgres connection used to look up and create
+// users. It is wired up once at startup via Init.
+var db *sql.DB
+
+// Init gives the auth package the Postgres connection opened by
+// api.SetupPostgres, so login and registration share the same pool.
+func Init(conn *sql.DB) {
+	db = conn
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// pqUniqueViolation is the Postgres error code for a unique constraint
+// violation, e.g. a duplicate username.
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const pqUniqueViolation = "23505"
+
+// tokenRequest is the JSON body accepted by POST /auth/token.
+type tokenRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// registerRequest is the JSON body accepted by POST /auth/register.
+type registerRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// Login handles POST /auth/token, exchanging a username/password pair for
+// a bearer token.
+func Login(c *gin.Context) {
+	var req tokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	var userID, passwordHash string
+	row := db.QueryRow("SELECT id, password_hash FROM users WHERE username = $1", req.Username)
+	if err := row.Scan(&userID, &passwordHash); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "invalid credentials"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if !CheckPassword(passwordHash, req.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "invalid credentials"})
+		return
+	}
+
+	token, err := IssueToken(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token})
+}
+
+// Register handles POST /auth/register, gated behind the ADMIN_TOKEN env
+// var so that only operators holding it can provision new accounts.
+func Register(c *gin.Context) {
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	if adminToken == "" || c.GetHeader("X-Admin-Token") != adminToken {
+		c.JSON(http.StatusForbidden, gin.H{"message": "not authorized to register users"})
+		return
+	}
+
+	var req registerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	passwordHash, err := HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	userID := uuid.NewString()
+	if _, err := db.Exec(
+		"INSERT INTO users (id, username, password_hash) VALUES ($1, $2, $3)",
+		userID, req.Username, passwordHash,
+	); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+			c.JSON(http.StatusConflict, gin.H{"message": "username already taken"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": userID, "username": req.Username})
+}
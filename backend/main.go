@@ -1,10 +1,18 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	api "github.com/el10savio/TODO-Fullstack-App-Go-Gin-Postgres-React/backend/api"
+	"github.com/el10savio/TODO-Fullstack-App-Go-Gin-Postgres-React/backend/auth"
+	migrations "github.com/el10savio/TODO-Fullstack-App-Go-Gin-Postgres-React/backend/migrations"
+	"github.com/el10savio/TODO-Fullstack-App-Go-Gin-Postgres-React/backend/tracing"
 
 	"github.com/gin-gonic/contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -53,8 +61,11 @@ func prometheusMiddleware() gin.HandlerFunc {
 		path := c.FullPath()
 		method := c.Request.Method
 
-		// Skip metrics for Prometheus endpoint itself
-		if path == "/metrics" {
+		// Skip metrics for the Prometheus endpoint itself, and for
+		// unmatched routes: FullPath is empty for those, and labeling on
+		// the raw request path would let a client leak unbounded label
+		// cardinality by probing random URLs.
+		if path == "" || path == "/metrics" {
 			c.Next()
 			return
 		}
@@ -64,10 +75,10 @@ func prometheusMiddleware() gin.HandlerFunc {
 
 		c.Next()
 
-		status := c.Writer.Status()
+		status := strconv.Itoa(c.Writer.Status())
 		duration := time.Since(start).Seconds()
 
-		httpRequestsTotal.WithLabelValues(method, path, http.StatusText(status)).Inc()
+		httpRequestsTotal.WithLabelValues(method, path, status).Inc()
 		httpRequestDuration.WithLabelValues(method, path).Observe(duration)
 	}
 }
@@ -79,25 +90,59 @@ func indexView(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "TODO APP"})
 }
 
+// healthzView handles GET /healthz, a liveness check: it reports ok as long
+// as the process is up, without touching any dependency.
+func healthzView(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// deprecatedRoutesEnabled reports whether the old GET-based item routes
+// should still be registered. They are on by default during the migration
+// to the RESTful /items routes; set DISABLE_DEPRECATED_ROUTES=true once
+// every client has moved over.
+func deprecatedRoutesEnabled() bool {
+	return os.Getenv("DISABLE_DEPRECATED_ROUTES") != "true"
+}
+
 // Setup Gin Routes
-func SetupRoutes() *gin.Engine {
+func SetupRoutes(a *api.API) *gin.Engine {
 	// Use Gin as router
 	router := gin.Default()
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
 	router.Use(cors.New(config))
 
-	// Add Prometheus middleware
+	// Add tracing and Prometheus middleware
+	router.Use(tracing.Middleware())
 	router.Use(prometheusMiddleware())
 
 	// Set route for index
 	router.GET("/", indexView)
 
-	// Set routes for API
-	router.GET("/items", api.TodoItems)
-	router.GET("/item/create/:item", api.CreateTodoItem)
-	router.GET("/item/update/:id/:done", api.UpdateTodoItem)
-	router.GET("/item/delete/:id", api.DeleteTodoItem)
+	// Liveness/readiness checks, split so a load balancer only pulls an
+	// instance out of rotation when its database is actually unreachable.
+	router.GET("/healthz", healthzView)
+	router.GET("/readyz", a.Readyz)
+
+	// Auth routes
+	router.POST("/auth/token", auth.Login)
+	router.POST("/auth/register", auth.Register)
+
+	// RESTful routes for the API, scoped to the authenticated user
+	items := router.Group("/items", auth.RequireUser())
+	items.GET("", a.TodoItems)
+	items.POST("", a.CreateItem)
+	items.PATCH("/:id", a.UpdateItem)
+	items.DELETE("/:id", a.DeleteItem)
+	items.GET("/events", a.ItemEvents)
+
+	// Deprecated aliases, kept for existing clients during the migration
+	if deprecatedRoutesEnabled() {
+		legacy := router.Group("/item", auth.RequireUser())
+		legacy.GET("/create/:item", a.CreateTodoItem)
+		legacy.GET("/update/:id/:done", a.UpdateTodoItem)
+		legacy.GET("/delete/:id", a.DeleteTodoItem)
+	}
 
 	// Add Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
@@ -105,9 +150,66 @@ func SetupRoutes() *gin.Engine {
 	return router
 }
 
+// runMigrateCommand implements the `migrate up|down|status` subcommand,
+// letting an operator manage the schema without starting the server.
+func runMigrateCommand(args []string) {
+	if len(args) != 1 {
+		log.Fatal("usage: main migrate up|down|status")
+	}
+
+	db, err := api.Connect()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
+	}
+
+	switch args[0] {
+	case "up":
+		if err := migrator.Up(); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("migrate: up to date")
+	case "down":
+		if err := migrator.Down(); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("migrate: rolled back one migration")
+	case "status":
+		entries, err := migrator.Status()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, entry := range entries {
+			state := "pending"
+			if entry.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%03d_%s: %s\n", entry.Version, entry.Name, state)
+		}
+	default:
+		log.Fatalf("usage: main migrate up|down|status (got %q)", args[0])
+	}
+}
+
 // Main function
 func main() {
-	api.SetupPostgres()
-	router := SetupRoutes()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(os.Args[2:])
+		return
+	}
+
+	if _, err := tracing.Init(context.Background()); err != nil {
+		log.Printf("tracing: failed to initialize, continuing without it: %v", err)
+	}
+
+	db := api.SetupPostgres()
+	auth.Init(db)
+	a := api.NewAPI(api.NewPostgresRepository(db))
+	router := SetupRoutes(a)
 	router.Run(":8081")
 }
@@ -0,0 +1,222 @@
+// Package migrations runs the versioned SQL files in sql/ against a
+// Postgres database, tracking which versions have already been applied in
+// a schema_migrations table.
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+// Migration is a single versioned schema change. DownSQL is empty when the
+// migration has no defined rollback (e.g. a lossy type change).
+type Migration struct {
+	Version int
+	Name    string
+	UpSQL   string
+	DownSQL string
+}
+
+// StatusEntry describes whether a migration has been applied.
+type StatusEntry struct {
+	Version int
+	Name    string
+	Applied bool
+}
+
+// Migrator applies and reports on migrations against db.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New returns a Migrator for db, loading the embedded SQL files.
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("migrations: failed to load sql files: %w", err)
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := sqlFiles.ReadDir("sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		isDown := strings.HasSuffix(name, ".down.sql")
+
+		base := strings.TrimSuffix(name, ".down.sql")
+		base = strings.TrimSuffix(base, ".sql")
+
+		parts := strings.SplitN(base, "_", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("migrations: unexpected file name %q", name)
+		}
+
+		version, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("migrations: unexpected file name %q: %w", name, err)
+		}
+
+		contents, err := sqlFiles.ReadFile(path.Join("sql", name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = m
+		}
+		if isDown {
+			m.DownSQL = string(contents)
+		} else {
+			m.UpSQL = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+func (m *Migrator) ensureSchema() error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`)
+	return err
+}
+
+func (m *Migrator) appliedVersions() (map[int]bool, error) {
+	if err := m.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// Up applies every pending migration, in order, each inside its own
+// transaction.
+func (m *Migrator) Up() error {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			continue
+		}
+
+		tx, err := m.db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(migration.UpSQL); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrations: failed to apply %03d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", migration.Version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrations: failed to record %03d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migrations: failed to commit %03d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Down rolls back the most recently applied migration.
+func (m *Migrator) Down() error {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return err
+	}
+
+	var last *Migration
+	for i := range m.migrations {
+		migration := m.migrations[i]
+		if applied[migration.Version] {
+			last = &migration
+		}
+	}
+	if last == nil {
+		return fmt.Errorf("migrations: nothing to roll back")
+	}
+	if last.DownSQL == "" {
+		return fmt.Errorf("migrations: no down migration available for %03d_%s", last.Version, last.Name)
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(last.DownSQL); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migrations: failed to roll back %03d_%s: %w", last.Version, last.Name, err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = $1", last.Version); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migrations: failed to unrecord %03d_%s: %w", last.Version, last.Name, err)
+	}
+
+	return tx.Commit()
+}
+
+// Status reports every known migration and whether it has been applied.
+func (m *Migrator) Status() ([]StatusEntry, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]StatusEntry, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		entries = append(entries, StatusEntry{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: applied[migration.Version],
+		})
+	}
+	return entries, nil
+}
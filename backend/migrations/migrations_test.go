@@ -0,0 +1,28 @@
+package migrations
+
+import "testing"
+
+func TestLoadMigrations_OrderedByVersion(t *testing.T) {
+	loaded, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations() error: %v", err)
+	}
+
+	if len(loaded) < 2 {
+		t.Fatalf("expected at least 2 migrations, got %d", len(loaded))
+	}
+
+	for i := 1; i < len(loaded); i++ {
+		if loaded[i].Version <= loaded[i-1].Version {
+			t.Fatalf("migrations not ordered by version: %d before %d", loaded[i-1].Version, loaded[i].Version)
+		}
+	}
+
+	if loaded[0].UpSQL == "" {
+		t.Fatalf("expected migration %03d to have up SQL", loaded[0].Version)
+	}
+
+	if loaded[0].DownSQL == "" {
+		t.Fatalf("expected migration %03d to have down SQL", loaded[0].Version)
+	}
+}